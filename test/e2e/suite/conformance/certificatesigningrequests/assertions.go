@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// CertificateAssertions declares expectations about the contents of a signed
+// certificate, so the suite can assert that an issuer actually honoured a
+// CertificateSigningRequest rather than merely signing something.
+type CertificateAssertions struct {
+	// ExpectedKeyUsages, if non-nil, is the exact set of key usages the
+	// signed certificate must have.
+	ExpectedKeyUsages *x509.KeyUsage
+
+	// ExpectedExtKeyUsages, if non-nil, is the exact set of extended key
+	// usages the signed certificate must have.
+	ExpectedExtKeyUsages []x509.ExtKeyUsage
+
+	// ExpectedIsCA, if non-nil, is the value the signed certificate's
+	// IsCA field must have.
+	ExpectedIsCA *bool
+
+	// ExpectedNotAfterDelta, if non-zero, bounds how far the signed
+	// certificate's NotAfter may drift from the requested duration.
+	ExpectedNotAfterDelta time.Duration
+
+	// ExpectedSubjectTemplate, if set, is compared against the signed
+	// certificate's Subject.
+	ExpectedSubjectTemplate *pkix.Name
+
+	// ExtraExtensionOIDs, if set, lists extension OIDs that must be present
+	// on the signed certificate, in addition to cert-manager's own checks.
+	ExtraExtensionOIDs []asn1.ObjectIdentifier
+
+	// IgnoredAssertions lists the names of Expected* fields above (e.g.
+	// "ExpectedIsCA") that this issuer is known not to honour, and which
+	// should therefore not cause a test failure. This allows, for example,
+	// an ACME issuer to declare that it ignores a requested IsCA.
+	IgnoredAssertions []string
+}
+
+// isIgnored reports whether assertion has been listed in IgnoredAssertions.
+func (a *CertificateAssertions) isIgnored(assertion string) bool {
+	for _, ignored := range a.IgnoredAssertions {
+		if ignored == assertion {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCertificateAssertions validates cert against s.CertificateAssertions,
+// skipping any assertion named in IgnoredAssertions, and returns an error
+// describing every violation found.
+func (s *Suite) checkCertificateAssertions(cert *x509.Certificate, requestedDuration time.Duration) error {
+	a := s.CertificateAssertions
+	if a == nil {
+		return nil
+	}
+
+	var errs []error
+
+	if a.ExpectedKeyUsages != nil && !a.isIgnored("ExpectedKeyUsages") {
+		if cert.KeyUsage != *a.ExpectedKeyUsages {
+			errs = append(errs, fmt.Errorf("expected key usages %v but got %v", *a.ExpectedKeyUsages, cert.KeyUsage))
+		}
+	}
+
+	if a.ExpectedExtKeyUsages != nil && !a.isIgnored("ExpectedExtKeyUsages") {
+		if !extKeyUsagesEqual(a.ExpectedExtKeyUsages, cert.ExtKeyUsage) {
+			errs = append(errs, fmt.Errorf("expected extended key usages %v but got %v", a.ExpectedExtKeyUsages, cert.ExtKeyUsage))
+		}
+	}
+
+	if a.ExpectedIsCA != nil && !a.isIgnored("ExpectedIsCA") {
+		if cert.IsCA != *a.ExpectedIsCA {
+			errs = append(errs, fmt.Errorf("expected IsCA=%v but got %v", *a.ExpectedIsCA, cert.IsCA))
+		}
+	}
+
+	if a.ExpectedNotAfterDelta != 0 && !a.isIgnored("ExpectedNotAfterDelta") {
+		actualDuration := cert.NotAfter.Sub(cert.NotBefore)
+		delta := actualDuration - requestedDuration
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta > a.ExpectedNotAfterDelta {
+			errs = append(errs, fmt.Errorf("expected certificate duration %s to be within %s of requested duration %s, but drifted by %s",
+				actualDuration, a.ExpectedNotAfterDelta, requestedDuration, delta))
+		}
+	}
+
+	if a.ExpectedSubjectTemplate != nil && !a.isIgnored("ExpectedSubjectTemplate") {
+		if cert.Subject.String() != a.ExpectedSubjectTemplate.String() {
+			errs = append(errs, fmt.Errorf("expected subject %q but got %q", a.ExpectedSubjectTemplate, cert.Subject.String()))
+		}
+	}
+
+	if len(a.ExtraExtensionOIDs) > 0 && !a.isIgnored("ExtraExtensionOIDs") {
+		for _, oid := range a.ExtraExtensionOIDs {
+			if !hasExtensionOID(cert, oid) {
+				errs = append(errs, fmt.Errorf("expected extension OID %v to be present but it was not", oid))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// extKeyUsagesEqual reports whether want and got contain the same set of
+// extended key usages, ignoring order.
+func extKeyUsagesEqual(want, got []x509.ExtKeyUsage) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	seen := make(map[x509.ExtKeyUsage]int)
+	for _, u := range want {
+		seen[u]++
+	}
+	for _, u := range got {
+		seen[u]--
+	}
+	for _, count := range seen {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hasExtensionOID reports whether cert contains an extension with the given
+// OID.
+func hasExtensionOID(cert *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/jetstack/cert-manager/test/e2e/framework"
+)
+
+// waitForExternalSignerReady polls WaitForSignerReadyFunc until it reports
+// ready, or returns an error once Timeouts.ExternalSignerReadyTimeout is
+// exceeded.
+func (s *Suite) waitForExternalSignerReady(f *framework.Framework) error {
+	return wait.PollImmediate(s.Timeouts.PollInterval, s.Timeouts.ExternalSignerReadyTimeout, func() (bool, error) {
+		return s.WaitForSignerReadyFunc(f)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/test/e2e/framework/helper/featureset"
+)
+
+// ConformanceProfile is the structured report produced by Suite.Report once
+// Define() has finished running under Ginkgo. It is intended to be published
+// so that third-party signers can advertise which features of the
+// CertificateSigningRequest conformance suite they support.
+type ConformanceProfile struct {
+	// IssuerName is the Suite.Name of the issuer under test.
+	IssuerName string `json:"issuerName"`
+	// SignerName is the CertificateSigningRequest signerName used across
+	// this run, recorded once from the first executed test case.
+	SignerName string `json:"signerName"`
+	// CertManagerVersion is the version of cert-manager the suite was built
+	// against.
+	CertManagerVersion string `json:"certManagerVersion"`
+	// SupportedFeatures lists the features this issuer declared support for.
+	SupportedFeatures []string `json:"supportedFeatures,omitempty"`
+	// UnsupportedFeatures lists the features this issuer declared it does not
+	// support.
+	UnsupportedFeatures []string `json:"unsupportedFeatures,omitempty"`
+	// SkippedFeatures lists every required feature of each test case that
+	// checkFeatures rejected, including features of that case which were
+	// themselves supported. Test cases skipped for any other reason are not
+	// recorded here.
+	SkippedFeatures []string `json:"skippedFeatures,omitempty"`
+	// FailedFeatures lists required features whose test cases ran but failed.
+	FailedFeatures []string `json:"failedFeatures,omitempty"`
+}
+
+// Report builds a ConformanceProfile for this run of the suite and writes it
+// to path as JSON or YAML, selected by the path's file extension (defaulting
+// to YAML). It should be called once Define() has completed, for example
+// from a Ginkgo ReportAfterSuite block.
+func (s *Suite) Report(path string) error {
+	profile := ConformanceProfile{
+		IssuerName:          s.Name,
+		SignerName:          s.signerName,
+		CertManagerVersion:  util.AppVersion,
+		SupportedFeatures:   featureNames(s.SupportedFeatures),
+		UnsupportedFeatures: featureNames(s.UnsupportedFeatures),
+		SkippedFeatures:     featureNames(s.skippedFeatures),
+		FailedFeatures:      featureNames(s.failedFeatures),
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasSuffix(path, ".json") {
+		data, err = json.MarshalIndent(profile, "", "  ")
+	} else {
+		data, err = yaml.Marshal(profile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal conformance profile: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write conformance profile to %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// featureNames returns the sorted string names of a FeatureSet, for stable
+// output in the conformance report.
+func featureNames(fs featureset.FeatureSet) []string {
+	names := make([]string, 0, len(fs))
+	for f := range fs {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -41,7 +42,7 @@ type Suite struct {
 	// CreateIssuerFunc is a function that provisions a new issuer resource and
 	// returns an SignerName to that Issuer that will be used as the SignerName
 	// on CertificateSigningRequest resources that this suite creates.
-	// This field must be provided.
+	// This field must be provided, unless ExternalSignerName is set instead.
 	CreateIssuerFunc func(*framework.Framework) string
 
 	// DeleteIssuerFunc is a function that is run after the test has completed
@@ -73,14 +74,86 @@ type Suite struct {
 	// nginx-ingress addon.
 	DomainSuffix string
 
+	// Timeouts holds the timeouts used by the suite's wait helpers. Any
+	// zero-valued fields are defaulted in complete().
+	Timeouts Timeouts
+
+	// CertificateAssertions, if set, declares expectations about the
+	// contents of every certificate signed during this run, beyond simply
+	// succeeding. Test cases should call checkCertificateAssertions on the
+	// parsed leaf certificate once signing has completed.
+	CertificateAssertions *CertificateAssertions
+
+	// TrustBundleAssertions, if set, declares expectations about how the
+	// issuer surfaces CA/chain information for the signed certificate. Test
+	// cases should call checkTrustBundleAssertions with the full chain
+	// returned by the issuer. Test cases that additionally require a
+	// companion trust ConfigMap/Secret should pass
+	// TrustBundleDistributionFeature as a requiredFeature to it() and call
+	// checkTrustBundleDistribution, which validates the bundle fetched via
+	// TrustBundleAssertions.FetchTrustBundleFunc.
+	TrustBundleAssertions *TrustBundleAssertions
+
+	// ExternalSignerName, if set, tells the suite to run against an external
+	// signer (e.g. a non-cert-manager controller watching a custom
+	// signerName) instead of creating an in-tree Issuer. When set,
+	// CreateIssuerFunc and DeleteIssuerFunc are skipped entirely and this
+	// value is used as the CertificateSigningRequest signerName directly.
+	ExternalSignerName string
+
+	// WaitForSignerReadyFunc, if set, is polled before each test case when
+	// ExternalSignerName is set, following the same (done bool, err error)
+	// convention as wait.ConditionFunc. It should report whether the external
+	// signer's controller is observed to be reconciling
+	// CertificateSigningRequests for ExternalSignerName. The suite polls this
+	// function and fails with a clear message if it never reports ready
+	// within a timeout, rather than a test simply timing out waiting for a
+	// CSR to be signed.
+	WaitForSignerReadyFunc func(*framework.Framework) (bool, error)
+
 	// UnsupportedFeatures is a list of features that are not supported by this
 	// invocation of the test suite.
 	// This is useful if a particular issuers explicitly does not support
 	// certain features due to restrictions in their implementation.
+	//
+	// Deprecated: prefer declaring SupportedFeatures instead, which is an
+	// opt-in allow-list and does not need to be updated every time a new
+	// feature test is added to the suite. UnsupportedFeatures is only
+	// consulted when SupportedFeatures is empty.
 	UnsupportedFeatures featureset.FeatureSet
 
+	// SupportedFeatures is the list of features that this invocation of the
+	// test suite declares support for. If non-empty, it is treated as an
+	// opt-in allow-list: any test case whose requiredFeatures are not all
+	// present in SupportedFeatures (or ExemptFeatures) will be skipped. This
+	// mirrors the SupportedFeatures model used by the Gateway API conformance
+	// suite, and means new feature tests do not require every existing issuer
+	// suite to be updated in order to keep passing.
+	// If left empty, the suite falls back to the opt-out behaviour driven by
+	// UnsupportedFeatures.
+	SupportedFeatures featureset.FeatureSet
+
+	// ExemptFeatures is a list of features that are always considered
+	// supported, regardless of what is declared in SupportedFeatures. This is
+	// useful for features that every issuer is expected to handle and that
+	// should not need to be listed explicitly.
+	ExemptFeatures featureset.FeatureSet
+
 	// completed is used internally to track whether Complete() has been called
 	completed bool
+
+	// signerName records the SignerName used across this run, for use by
+	// Report. It is set from the first executed test case and then checked
+	// for consistency on every subsequent one, since a single Suite/Report
+	// is meant to describe one issuer under test, not whichever test case
+	// happened to run last.
+	signerName string
+
+	// skippedFeatures and failedFeatures record, across the lifetime of the
+	// suite, which required features caused a test case to be skipped or
+	// failed. They back the conformance profile produced by Report.
+	skippedFeatures featureset.FeatureSet
+	failedFeatures  featureset.FeatureSet
 }
 
 // complete will validate configuration and set default values.
@@ -89,12 +162,40 @@ func (s *Suite) complete(f *framework.Framework) {
 		s.DomainSuffix = f.Config.Addons.IngressController.Domain
 	}
 
+	if s.ExternalSignerName == "" && s.CreateIssuerFunc == nil {
+		Fail("either CreateIssuerFunc or ExternalSignerName must be set on Suite")
+	}
+
+	s.Timeouts.setDefaults()
+
 	s.completed = true
 }
 
+// addSkippedFeature and addFailedFeature record features for the
+// conformance profile produced by Report. s.it() is invoked while Ginkgo is
+// still constructing the spec tree, i.e. before any BeforeEach (and
+// therefore before complete()) has run, so skippedFeatures/failedFeatures
+// are lazily initialized here rather than relying on complete() to do it.
+func (s *Suite) addSkippedFeature(f featureset.Feature) {
+	if s.skippedFeatures == nil {
+		s.skippedFeatures = make(featureset.FeatureSet)
+	}
+	s.skippedFeatures.Add(f)
+}
+
+func (s *Suite) addFailedFeature(f featureset.Feature) {
+	if s.failedFeatures == nil {
+		s.failedFeatures = make(featureset.FeatureSet)
+	}
+	s.failedFeatures.Add(f)
+}
+
 // it is called by the tests to in Define() to setup and run the test
 func (s *Suite) it(f *framework.Framework, name string, fn func(string), requiredFeatures ...featureset.Feature) {
 	if !s.checkFeatures(requiredFeatures...) {
+		for _, rf := range requiredFeatures {
+			s.addSkippedFeature(rf)
+		}
 		fmt.Fprintln(GinkgoWriter, "skipping case due to unsupported features")
 		return
 	}
@@ -105,23 +206,133 @@ func (s *Suite) it(f *framework.Framework, name string, fn func(string), require
 				feature.ExperimentalCertificateSigningRequestControllers)
 		}
 
-		By("Creating an issuer resource")
-		signerName := s.CreateIssuerFunc(f)
-		defer func() {
-			if s.DeleteIssuerFunc != nil {
-				By("Cleaning up the issuer resource")
-				s.DeleteIssuerFunc(f, signerName)
+		var signerName string
+		if s.ExternalSignerName != "" {
+			signerName = s.ExternalSignerName
+			if s.WaitForSignerReadyFunc != nil {
+				By("Waiting for the external signer controller to be ready")
+				if err := s.waitForExternalSignerReady(f); err != nil {
+					framework.Failf("external signer %q never became ready: %v", signerName, err)
+				}
+			}
+		} else {
+			By("Creating an issuer resource")
+			var err error
+			signerName, err = s.createIssuerWithTimeout(f)
+			if err != nil {
+				framework.Failf("%v", err)
 			}
-		}()
+			defer func() {
+				if s.DeleteIssuerFunc != nil {
+					By("Cleaning up the issuer resource")
+					s.DeleteIssuerFunc(f, signerName)
+				}
+			}()
+		}
+		s.recordSignerName(signerName)
+		completed := false
+		defer func() { s.recordResult(!completed, requiredFeatures...) }()
 		fn(signerName)
+		completed = true
 	})
 }
 
+// createIssuerWithTimeout runs CreateIssuerFunc and fails with a clear error
+// if it does not return within Timeouts.IssuerReadyTimeout, rather than
+// leaving the suite to hang indefinitely if an issuer never becomes ready.
+func (s *Suite) createIssuerWithTimeout(f *framework.Framework) (string, error) {
+	done := make(chan string, 1)
+	go func() {
+		done <- s.CreateIssuerFunc(f)
+	}()
+	select {
+	case signerName := <-done:
+		return signerName, nil
+	case <-time.After(s.Timeouts.IssuerReadyTimeout):
+		return "", fmt.Errorf("timed out after %s waiting for CreateIssuerFunc to return a ready issuer", s.Timeouts.IssuerReadyTimeout)
+	}
+}
+
+// provision runs ProvisionFunc, if set, against csr and signer before the
+// CertificateSigningRequest is created, failing with a clear error if it
+// does not complete within Timeouts.ProvisionTimeout.
+func (s *Suite) provision(f *framework.Framework, csr *certificatesv1.CertificateSigningRequest, signer crypto.Signer) {
+	if s.ProvisionFunc == nil {
+		return
+	}
+	By("Provisioning resources required by the issuer")
+	done := make(chan struct{})
+	go func() {
+		s.ProvisionFunc(f, csr, signer)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.Timeouts.ProvisionTimeout):
+		framework.Failf("timed out after %s waiting for ProvisionFunc to complete", s.Timeouts.ProvisionTimeout)
+	}
+}
+
+// deprovision runs DeProvisionFunc, if set, to clean up anything provision
+// created for csr.
+func (s *Suite) deprovision(f *framework.Framework, csr *certificatesv1.CertificateSigningRequest) {
+	if s.DeProvisionFunc == nil {
+		return
+	}
+	By("Cleaning up provisioned resources")
+	s.DeProvisionFunc(f, csr)
+}
+
+// recordSignerName records signerName as the stable identity of the issuer
+// under test for this run. A Suite/Report describes a single issuer, so if a
+// later test case observes a different signerName than the first one did,
+// that is a configuration bug in the calling test (e.g. CreateIssuerFunc
+// returning a fresh signerName per call) and is surfaced as a clear failure
+// rather than silently overwriting the recorded value.
+func (s *Suite) recordSignerName(signerName string) {
+	if s.signerName == "" {
+		s.signerName = signerName
+		return
+	}
+	if s.signerName != signerName {
+		framework.Failf("observed signerName %q for this test case, but a previous test case in the same run observed %q; "+
+			"a Suite describes a single issuer and must use a stable signerName across all its test cases", signerName, s.signerName)
+	}
+}
+
+// recordResult records, for the test case that just ran, whether its
+// requiredFeatures should be considered failed. It is called via defer so
+// that it also runs when fn fails: Ginkgo reports a failure by panicking out
+// of fn, so failed is true whenever fn did not return normally.
+func (s *Suite) recordResult(failed bool, requiredFeatures ...featureset.Feature) {
+	if failed {
+		for _, rf := range requiredFeatures {
+			s.addFailedFeature(rf)
+		}
+	}
+}
+
 // checkFeatures is a helper function that is used to ensure that the features
 // required for a given test case are supported by the suite.
 // It will return 'true' if all features are supported and the test should run,
 // or return 'false' if any required feature is not supported.
 func (s *Suite) checkFeatures(fs ...featureset.Feature) bool {
+	// SupportedFeatures, when set, is an opt-in allow-list: a test only runs
+	// if every required feature is either declared supported or exempt.
+	if len(s.SupportedFeatures) > 0 {
+		for _, f := range fs {
+			if s.ExemptFeatures.Contains(f) {
+				continue
+			}
+			if !s.SupportedFeatures.Contains(f) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Fall back to the opt-out behaviour: a test runs unless one of its
+	// required features has been explicitly marked unsupported.
 	unsupported := make(featureset.FeatureSet)
 	for _, f := range fs {
 		if s.UnsupportedFeatures.Contains(f) {
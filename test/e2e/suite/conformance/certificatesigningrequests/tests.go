@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/jetstack/cert-manager/test/e2e/framework"
+)
+
+// Define registers the conformance test cases for this Suite under Ginkgo.
+// It should be called once per issuer from that issuer's own _test.go file,
+// after populating the Suite's fields.
+func (s *Suite) Define() {
+	Describe("CertificateSigningRequest conformance: "+s.Name, func() {
+		f := framework.NewDefaultFramework("certificatesigningrequests-conformance")
+
+		BeforeEach(func() {
+			s.complete(f)
+		})
+
+		s.it(f, "should sign a CertificateSigningRequest and satisfy any declared certificate and trust-bundle assertions", func(signerName string) {
+			s.runSignTest(f, signerName)
+		})
+
+		s.it(f, "should publish a companion trust bundle for the signer", func(signerName string) {
+			s.runTrustBundleDistributionTest(f, signerName)
+		}, TrustBundleDistributionFeature)
+	})
+}
+
+// runSignTest creates a CertificateSigningRequest for signerName, waits for
+// it to be signed, and validates the result against s.CertificateAssertions.
+// This is what exercises checkCertificateAssertions; without it, a declared
+// CertificateAssertions would never actually be checked against anything.
+func (s *Suite) runSignTest(f *framework.Framework, signerName string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+
+	csrPEM, err := buildCSRPEM(key, pkix.Name{CommonName: "conformance.cert-manager.io"})
+	Expect(err).NotTo(HaveOccurred())
+
+	expirationSeconds := int32(3600)
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "cert-manager-conformance-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           csrPEM,
+			SignerName:        signerName,
+			Usages:            []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment},
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+
+	s.provision(f, csr, key)
+	defer s.deprovision(f, csr)
+
+	By("Creating a CertificateSigningRequest")
+	created, err := f.KubeClientSet.CertificatesV1().CertificateSigningRequests().Create(context.Background(), csr, metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	By("Waiting for the CertificateSigningRequest to be signed")
+	signed, err := s.waitForCSRSigned(f, created.Name)
+	Expect(err).NotTo(HaveOccurred())
+
+	chain, err := parsePEMCertificates(signed.Status.Certificate)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(chain).NotTo(BeEmpty())
+
+	By("Checking declared certificate-content assertions")
+	Expect(s.checkCertificateAssertions(chain[0], requestedDuration(csr))).NotTo(HaveOccurred())
+
+	By("Checking declared trust-bundle assertions")
+	Expect(s.checkTrustBundleAssertions(f, signerName, chain)).NotTo(HaveOccurred())
+}
+
+// runTrustBundleDistributionTest signs a CertificateSigningRequest and then
+// validates that the issuer also publishes a companion trust bundle
+// containing the roots returned by TrustBundleAssertions.FetchCAFunc. This is
+// what exercises checkTrustBundleDistribution; without it, a declared
+// TrustBundleAssertions.FetchTrustBundleFunc would never actually be checked.
+func (s *Suite) runTrustBundleDistributionTest(f *framework.Framework, signerName string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+
+	csrPEM, err := buildCSRPEM(key, pkix.Name{CommonName: "conformance-trust-bundle.cert-manager.io"})
+	Expect(err).NotTo(HaveOccurred())
+
+	expirationSeconds := int32(3600)
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "cert-manager-conformance-trust-bundle-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           csrPEM,
+			SignerName:        signerName,
+			Usages:            []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment},
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+
+	s.provision(f, csr, key)
+	defer s.deprovision(f, csr)
+
+	By("Creating a CertificateSigningRequest")
+	created, err := f.KubeClientSet.CertificatesV1().CertificateSigningRequests().Create(context.Background(), csr, metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	By("Waiting for the CertificateSigningRequest to be signed")
+	signed, err := s.waitForCSRSigned(f, created.Name)
+	Expect(err).NotTo(HaveOccurred())
+
+	chain, err := parsePEMCertificates(signed.Status.Certificate)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(chain).NotTo(BeEmpty())
+
+	By("Checking declared certificate-content assertions")
+	Expect(s.checkCertificateAssertions(chain[0], requestedDuration(csr))).NotTo(HaveOccurred())
+
+	Expect(s.TrustBundleAssertions).NotTo(BeNil(), "TrustBundleDistributionFeature requires TrustBundleAssertions to be set")
+
+	By("Fetching the expected CA roots for this signer")
+	roots, err := s.TrustBundleAssertions.FetchCAFunc(f, signerName)
+	Expect(err).NotTo(HaveOccurred())
+
+	By("Checking the companion trust bundle is published and contains the expected roots")
+	Expect(s.checkTrustBundleDistribution(f, signerName, roots)).NotTo(HaveOccurred())
+}
+
+// waitForCSRSigned polls the named CertificateSigningRequest until its
+// status.certificate is populated, it is denied, or it fails, bounded by
+// Timeouts.CSRSignedTimeout.
+func (s *Suite) waitForCSRSigned(f *framework.Framework, name string) (*certificatesv1.CertificateSigningRequest, error) {
+	var result *certificatesv1.CertificateSigningRequest
+	err := wait.PollImmediate(s.Timeouts.PollInterval, s.Timeouts.CSRSignedTimeout, func() (bool, error) {
+		csr, err := f.KubeClientSet.CertificatesV1().CertificateSigningRequests().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range csr.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			if cond.Type == certificatesv1.CertificateDenied {
+				return false, fmt.Errorf("CertificateSigningRequest %q was denied: %s", name, cond.Message)
+			}
+			if cond.Type == certificatesv1.CertificateFailed {
+				return false, fmt.Errorf("CertificateSigningRequest %q failed: %s", name, cond.Message)
+			}
+		}
+		if len(csr.Status.Certificate) == 0 {
+			return false, nil
+		}
+		result = csr
+		return true, nil
+	})
+	return result, err
+}
+
+// buildCSRPEM generates a PEM-encoded PKCS#10 certificate request for key.
+func buildCSRPEM(key *rsa.PrivateKey, subject pkix.Name) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		Subject:            subject,
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// requestedDuration returns the duration requested via csr.Spec.ExpirationSeconds,
+// or 0 if none was requested.
+func requestedDuration(csr *certificatesv1.CertificateSigningRequest) time.Duration {
+	if csr.Spec.ExpirationSeconds == nil {
+		return 0
+	}
+	return time.Duration(*csr.Spec.ExpirationSeconds) * time.Second
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import "time"
+
+// Timeouts collects the various timeouts used by the conformance suite's
+// wait helpers, modelled on the timeout constants exported by Kubernetes-style
+// e2e frameworks (e.g. PodStartTimeout, NamespaceCleanupTimeout). Slow issuers
+// (ACME with real DNS-01 propagation, HSM-backed CA signing) can lengthen
+// these to avoid flakes, while fast in-memory issuers can tighten them to
+// fail fast in CI.
+type Timeouts struct {
+	// CSRSignedTimeout is the maximum time to wait for a
+	// CertificateSigningRequest to be signed.
+	CSRSignedTimeout time.Duration
+
+	// IssuerReadyTimeout is the maximum time to wait for CreateIssuerFunc to
+	// return a ready issuer.
+	IssuerReadyTimeout time.Duration
+
+	// ProvisionTimeout is the maximum time to wait for ProvisionFunc to
+	// return.
+	ProvisionTimeout time.Duration
+
+	// ExternalSignerReadyTimeout is the maximum time to wait for
+	// WaitForSignerReadyFunc to report that an external signer's controller
+	// is reconciling.
+	ExternalSignerReadyTimeout time.Duration
+
+	// PollInterval is the interval used while polling for the above
+	// conditions.
+	PollInterval time.Duration
+}
+
+// Default timeouts used to populate any zero-valued fields of Timeouts in
+// Suite.complete.
+const (
+	defaultCSRSignedTimeout           = time.Minute * 2
+	defaultIssuerReadyTimeout         = time.Minute * 2
+	defaultProvisionTimeout           = time.Minute * 1
+	defaultExternalSignerReadyTimeout = time.Minute * 2
+	defaultPollInterval               = time.Second * 2
+)
+
+// setDefaults fills in any unset fields of t with the suite's default
+// timeouts.
+func (t *Timeouts) setDefaults() {
+	if t.CSRSignedTimeout == 0 {
+		t.CSRSignedTimeout = defaultCSRSignedTimeout
+	}
+	if t.IssuerReadyTimeout == 0 {
+		t.IssuerReadyTimeout = defaultIssuerReadyTimeout
+	}
+	if t.ProvisionTimeout == 0 {
+		t.ProvisionTimeout = defaultProvisionTimeout
+	}
+	if t.ExternalSignerReadyTimeout == 0 {
+		t.ExternalSignerReadyTimeout = defaultExternalSignerReadyTimeout
+	}
+	if t.PollInterval == 0 {
+		t.PollInterval = defaultPollInterval
+	}
+}
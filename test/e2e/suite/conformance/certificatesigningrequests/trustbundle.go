@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/jetstack/cert-manager/test/e2e/framework"
+	"github.com/jetstack/cert-manager/test/e2e/framework/helper/featureset"
+)
+
+// TrustBundleDistributionFeature is passed as a requiredFeature to it() to
+// gate test cases that assert an issuer publishes a companion trust
+// ConfigMap/Secret, in addition to the per-CertificateSigningRequest
+// status.certificate chain. Issuers that support this should be declared via
+// Suite.SupportedFeatures (or simply not listed in Suite.UnsupportedFeatures).
+const TrustBundleDistributionFeature = featureset.Feature("TrustBundleDistribution")
+
+// TrustBundleAssertions declares expectations about how a signer surfaces its
+// CA/trust information, on top of the leaf certificate itself. This covers
+// ground that varies significantly between external providers (ONAP
+// CertService, step-ca, private ACME) and has no other conformance coverage:
+// whether the returned chain actually chains up to a trusted root, whether
+// intermediates are ordered correctly, and whether AIA/CRL/OCSP extensions
+// and a companion trust bundle are published.
+type TrustBundleAssertions struct {
+	// FetchCAFunc fetches the root(s) that the given signerName is expected
+	// to chain up to. This field must be provided to enable any of the
+	// assertions below.
+	FetchCAFunc func(*framework.Framework, string) ([]*x509.Certificate, error)
+
+	// FetchTrustBundleFunc, if set, fetches the PEM-encoded contents of the
+	// companion trust ConfigMap/Secret that the issuer is expected to
+	// publish for signerName. It backs test cases gated by
+	// TrustBundleDistributionFeature; see checkTrustBundleDistribution.
+	FetchTrustBundleFunc func(*framework.Framework, string) ([]byte, error)
+
+	// ExpectIntermediatesOrdered asserts that any intermediates returned
+	// alongside the leaf are ordered from leaf to root.
+	ExpectIntermediatesOrdered bool
+
+	// ExpectAIA asserts that the leaf certificate's Authority Information
+	// Access extension carries at least one CA issuers URL. OCSP server
+	// entries in the same extension are checked by ExpectOCSP, not here.
+	ExpectAIA bool
+
+	// ExpectCRLDistributionPoints asserts that the leaf certificate's CRL
+	// distribution points extension is populated.
+	ExpectCRLDistributionPoints bool
+
+	// ExpectOCSP asserts that the leaf certificate's OCSP server extension
+	// (part of AIA) is populated.
+	ExpectOCSP bool
+}
+
+// checkTrustBundleAssertions validates that leaf chains up to a root fetched
+// via FetchCAFunc, that intermediates are ordered correctly, and that the
+// requested extensions are present. chain is the full signed chain as
+// returned by the issuer, leaf first.
+func (s *Suite) checkTrustBundleAssertions(f *framework.Framework, signerName string, chain []*x509.Certificate) error {
+	a := s.TrustBundleAssertions
+	if a == nil || a.FetchCAFunc == nil {
+		return nil
+	}
+	if len(chain) == 0 {
+		return fmt.Errorf("no certificate chain was returned to check trust bundle assertions against")
+	}
+
+	leaf := chain[0]
+	intermediates := chain[1:]
+
+	var errs []error
+
+	if a.ExpectIntermediatesOrdered && len(intermediates) > 1 {
+		cur := leaf
+		for _, intermediate := range intermediates {
+			if err := cur.CheckSignatureFrom(intermediate); err != nil {
+				errs = append(errs, fmt.Errorf("intermediates are not ordered leaf-to-root: %w", err))
+				break
+			}
+			cur = intermediate
+		}
+	}
+
+	roots, err := a.FetchCAFunc(f, signerName)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to fetch CA roots for signer %q: %w", signerName, err))
+	} else {
+		pool := x509.NewCertPool()
+		for _, root := range roots {
+			pool.AddCert(root)
+		}
+		intermediatePool := x509.NewCertPool()
+		for _, intermediate := range intermediates {
+			intermediatePool.AddCert(intermediate)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: pool, Intermediates: intermediatePool}); err != nil {
+			errs = append(errs, fmt.Errorf("leaf certificate does not chain up to a trusted root for signer %q: %w", signerName, err))
+		}
+	}
+
+	if a.ExpectAIA && len(leaf.IssuingCertificateURL) == 0 {
+		errs = append(errs, fmt.Errorf("expected leaf certificate to have an Authority Information Access CA issuers URL but it had none"))
+	}
+
+	if a.ExpectCRLDistributionPoints && len(leaf.CRLDistributionPoints) == 0 {
+		errs = append(errs, fmt.Errorf("expected leaf certificate to have CRL distribution points but it had none"))
+	}
+
+	if a.ExpectOCSP && len(leaf.OCSPServer) == 0 {
+		errs = append(errs, fmt.Errorf("expected leaf certificate to have an OCSP server but it had none"))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// checkTrustBundleDistribution verifies that the companion trust bundle
+// fetched via FetchTrustBundleFunc is non-empty, parses as PEM-encoded
+// certificates, and contains every one of roots. Test cases for
+// TrustBundleDistributionFeature should call this once FetchCAFunc's roots
+// are known.
+func (s *Suite) checkTrustBundleDistribution(f *framework.Framework, signerName string, roots []*x509.Certificate) error {
+	a := s.TrustBundleAssertions
+	if a == nil || a.FetchTrustBundleFunc == nil {
+		return fmt.Errorf("TrustBundleDistributionFeature requires TrustBundleAssertions.FetchTrustBundleFunc to be set")
+	}
+
+	bundle, err := a.FetchTrustBundleFunc(f, signerName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch companion trust bundle for signer %q: %w", signerName, err)
+	}
+	if len(bundle) == 0 {
+		return fmt.Errorf("companion trust bundle for signer %q was empty", signerName)
+	}
+
+	bundleCerts, err := parsePEMCertificates(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to parse companion trust bundle for signer %q: %w", signerName, err)
+	}
+	if len(bundleCerts) == 0 {
+		return fmt.Errorf("companion trust bundle for signer %q did not contain any certificates", signerName)
+	}
+
+	var errs []error
+	for _, root := range roots {
+		if !containsCertificate(bundleCerts, root) {
+			errs = append(errs, fmt.Errorf("companion trust bundle for signer %q did not contain expected root %q", signerName, root.Subject))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// parsePEMCertificates decodes every CERTIFICATE PEM block in data.
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// containsCertificate reports whether certs contains want.
+func containsCertificate(certs []*x509.Certificate, want *x509.Certificate) bool {
+	for _, cert := range certs {
+		if cert.Equal(want) {
+			return true
+		}
+	}
+	return false
+}